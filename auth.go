@@ -0,0 +1,60 @@
+package main
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/vartanbeno/go-reddit/v2/reddit"
+)
+
+// authenticatedRequestsPerMinute is the quota Reddit grants to an
+// authenticated (OAuth) client, as opposed to the much lower anonymous quota.
+const authenticatedRequestsPerMinute = 600
+
+// RedditCredentials holds the OAuth2 script-app credentials used to
+// authenticate with the Reddit API.
+type RedditCredentials struct {
+	ID       string
+	Secret   string
+	Username string
+	Password string
+}
+
+// loadRedditCredentials reads credentials from the environment.
+//
+// go-reddit/v2's client only supports the password grant (reddit.Credentials
+// has no refresh-token field), so there's no way to authenticate with just a
+// stored refresh token; Username/Password are required.
+func loadRedditCredentials() (RedditCredentials, error) {
+	creds := RedditCredentials{
+		ID:       os.Getenv("REDDIT_CLIENT_ID"),
+		Secret:   os.Getenv("REDDIT_CLIENT_SECRET"),
+		Username: os.Getenv("REDDIT_USERNAME"),
+		Password: os.Getenv("REDDIT_PASSWORD"),
+	}
+
+	if creds.ID == "" || creds.Secret == "" {
+		return creds, fmt.Errorf("REDDIT_CLIENT_ID and REDDIT_CLIENT_SECRET must be set")
+	}
+	if creds.Username == "" || creds.Password == "" {
+		return creds, fmt.Errorf("REDDIT_USERNAME and REDDIT_PASSWORD must be set")
+	}
+
+	return creds, nil
+}
+
+// newAuthenticatedClient builds a *reddit.Client shared across all monitor
+// goroutines, authenticated via OAuth so we get the higher authenticated
+// request quota instead of falling back to reddit.DefaultClient().
+func newAuthenticatedClient(creds RedditCredentials) (*reddit.Client, error) {
+	client, err := reddit.NewClient(reddit.Credentials{
+		ID:       creds.ID,
+		Secret:   creds.Secret,
+		Username: creds.Username,
+		Password: creds.Password,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("authenticate with reddit: %w", err)
+	}
+	return client, nil
+}
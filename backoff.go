@@ -0,0 +1,18 @@
+package main
+
+import "time"
+
+const maxPollBackoff = time.Minute
+
+// nextBackoff doubles current (starting from one second) up to a ceiling,
+// used to back off polling a target that's erroring instead of hammering it.
+func nextBackoff(current time.Duration) time.Duration {
+	if current <= 0 {
+		return time.Second
+	}
+	next := current * 2
+	if next > maxPollBackoff {
+		return maxPollBackoff
+	}
+	return next
+}
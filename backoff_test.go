@@ -0,0 +1,28 @@
+package main
+
+import (
+	"testing"
+	"time"
+)
+
+func TestNextBackoff(t *testing.T) {
+	tests := []struct {
+		name    string
+		current time.Duration
+		want    time.Duration
+	}{
+		{"zero starts at one second", 0, time.Second},
+		{"negative starts at one second", -time.Second, time.Second},
+		{"doubles", 4 * time.Second, 8 * time.Second},
+		{"caps at ceiling", maxPollBackoff, maxPollBackoff},
+		{"doubling past ceiling is capped", maxPollBackoff - time.Second, maxPollBackoff},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := nextBackoff(tt.current); got != tt.want {
+				t.Errorf("nextBackoff(%v) = %v, want %v", tt.current, got, tt.want)
+			}
+		})
+	}
+}
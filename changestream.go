@@ -0,0 +1,187 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+var (
+	matchesCollection      *mongo.Collection
+	streamResumeCollection *mongo.Collection
+)
+
+// Match is a single rule hit written to the matches collection. Downstream
+// consumers watch this collection via a change stream rather than polling.
+type Match struct {
+	RuleName      string     `bson:"rule_name"`
+	ItemType      string     `bson:"item_type"` // "post" or "comment"
+	ItemID        string     `bson:"item_id"`
+	PostID        string     `bson:"post_id,omitempty"`
+	Subreddit     string     `bson:"subreddit"`
+	Rendered      string     `bson:"rendered"`
+	Captures      []string   `bson:"captures"`
+	MatchedAt     time.Time  `bson:"matched_at"`
+	DownstreamRef string     `bson:"downstream_ref,omitempty"` // e.g. the webhook/reply ID a consumer created for this match
+	DeletedAt     *time.Time `bson:"deleted_at,omitempty"`
+}
+
+// streamResumeToken persists the last resume token seen for a given watcher,
+// so a restarted consumer can pick back up without reprocessing or losing
+// events.
+type streamResumeToken struct {
+	WatcherName string    `bson:"watcher_name"`
+	ResumeToken bson.Raw  `bson:"resume_token"`
+	UpdatedAt   time.Time `bson:"updated_at"`
+}
+
+// saveMatch upserts a Match document keyed by (rule_name, item_id), which
+// triggers the change stream watched by downstream consumers as either an
+// insert (first time a rule fires for an item) or an update (the item was
+// edited and the rule fired again).
+func saveMatch(match Match) error {
+	filter := bson.M{"rule_name": match.RuleName, "item_id": match.ItemID}
+	update := bson.M{"$set": match}
+	opts := options.Update().SetUpsert(true)
+
+	_, err := matchesCollection.UpdateOne(context.TODO(), filter, update, opts)
+	if err != nil {
+		return fmt.Errorf("upsert match: %w", err)
+	}
+	return nil
+}
+
+// matchDownstreamRef returns the downstream_ref previously stored for
+// (ruleName, itemID), or "" if no match has been saved yet. Carrying this
+// forward on re-save lets a downstream consumer edit its existing reply/row
+// in place instead of creating a duplicate when a comment is edited.
+func matchDownstreamRef(ruleName, itemID string) string {
+	var existing Match
+	filter := bson.M{"rule_name": ruleName, "item_id": itemID}
+	err := matchesCollection.FindOne(context.TODO(), filter).Decode(&existing)
+	if err != nil {
+		if !errors.Is(err, mongo.ErrNoDocuments) {
+			log.Println("Error looking up existing match:", err)
+		}
+		return ""
+	}
+	return existing.DownstreamRef
+}
+
+// markMatchDeleted flags every match for itemID as deleted, so a consumer
+// watching the change stream can retract whatever it did downstream (e.g.
+// delete a reply) instead of acting on a comment that no longer exists.
+func markMatchDeleted(itemID string) error {
+	filter := bson.M{"item_id": itemID}
+	now := time.Now()
+	update := bson.M{"$set": bson.M{"deleted_at": now}}
+
+	_, err := matchesCollection.UpdateMany(context.TODO(), filter, update)
+	if err != nil {
+		return fmt.Errorf("mark match deleted: %w", err)
+	}
+	return nil
+}
+
+// loadResumeToken returns the persisted resume token for watcherName, or nil
+// if none has been saved yet.
+func loadResumeToken(watcherName string) bson.Raw {
+	var stored streamResumeToken
+	filter := bson.M{"watcher_name": watcherName}
+	err := streamResumeCollection.FindOne(context.TODO(), filter).Decode(&stored)
+	if err != nil {
+		if !errors.Is(err, mongo.ErrNoDocuments) {
+			log.Println("Error loading resume token:", err)
+		}
+		return nil
+	}
+	return stored.ResumeToken
+}
+
+// saveResumeToken persists the most recently processed resume token for
+// watcherName.
+func saveResumeToken(watcherName string, token bson.Raw) {
+	filter := bson.M{"watcher_name": watcherName}
+	update := bson.M{"$set": streamResumeToken{
+		WatcherName: watcherName,
+		ResumeToken: token,
+		UpdatedAt:   time.Now(),
+	}}
+
+	opts := options.Update().SetUpsert(true)
+	_, err := streamResumeCollection.UpdateOne(context.TODO(), filter, update, opts)
+	if err != nil {
+		log.Println("Error saving resume token:", err)
+	}
+}
+
+// watchCollection opens a change stream against coll, filtered to inserts and
+// updates, resuming from watcherName's last saved token if one exists, and
+// invokes handler for every event. It blocks until ctx is cancelled or the
+// stream errors.
+func watchCollection(ctx context.Context, coll *mongo.Collection, watcherName string, handler func(bson.Raw)) error {
+	pipeline := mongo.Pipeline{
+		bson.D{{Key: "$match", Value: bson.D{
+			{Key: "operationType", Value: bson.D{{Key: "$in", Value: bson.A{"insert", "update"}}}},
+		}}},
+	}
+
+	opts := options.ChangeStream().SetMaxAwaitTime(2 * time.Second).SetFullDocument(options.UpdateLookup)
+	if token := loadResumeToken(watcherName); token != nil {
+		opts.SetResumeAfter(token)
+	}
+
+	stream, err := coll.Watch(ctx, pipeline, opts)
+	if err != nil {
+		return fmt.Errorf("open change stream for %s: %w", watcherName, err)
+	}
+	defer func() { _ = stream.Close(ctx) }()
+
+	for stream.Next(ctx) {
+		handler(stream.Current)
+		saveResumeToken(watcherName, stream.ResumeToken())
+	}
+
+	return stream.Err()
+}
+
+// webhookMatchConsumer is a sample downstream consumer: it watches the
+// matches collection via change stream and forwards each one to a webhook
+// URL. This is the integration point for Slack/Discord/etc. without coupling
+// them into the main poll loop.
+func webhookMatchConsumer(ctx context.Context, webhookURL string) {
+	err := watchCollection(ctx, matchesCollection, "webhook_consumer", func(raw bson.Raw) {
+		var event struct {
+			FullDocument Match `bson:"fullDocument"`
+		}
+		if err := bson.Unmarshal(raw, &event); err != nil {
+			log.Println("Error decoding match change event:", err)
+			return
+		}
+
+		body, err := json.Marshal(event.FullDocument)
+		if err != nil {
+			log.Println("Error marshaling match for webhook:", err)
+			return
+		}
+
+		resp, err := http.Post(webhookURL, "application/json", bytes.NewReader(body))
+		if err != nil {
+			log.Println("Error posting match to webhook:", err)
+			return
+		}
+		_ = resp.Body.Close()
+	})
+	if err != nil && ctx.Err() == nil {
+		log.Println("Webhook consumer stream ended with error:", err)
+	}
+}
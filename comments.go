@@ -0,0 +1,173 @@
+package main
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/vartanbeno/go-reddit/v2/reddit"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+var trackedCommentsCollection *mongo.Collection
+
+// TrackedComment is the per-comment record used to detect edits, deletions,
+// and repeat rule matches across polls.
+type TrackedComment struct {
+	CommentID        string     `bson:"comment_id"`
+	PostID           string     `bson:"post_id"`
+	BodyHash         string     `bson:"body_hash"`
+	EditedAt         time.Time  `bson:"edited_at"`
+	LastMatchedRules []string   `bson:"last_matched_rules"`
+	DownstreamRef    string     `bson:"downstream_ref,omitempty"`
+	DeletedAt        *time.Time `bson:"deleted_at,omitempty"`
+}
+
+// hashCommentBody returns a stable content hash used to detect comment edits
+// independent of whatever Edited.Time Reddit reports.
+func hashCommentBody(body string) string {
+	sum := sha256.Sum256([]byte(body))
+	return hex.EncodeToString(sum[:])
+}
+
+// getTrackedComment loads the stored record for commentID, if any.
+func getTrackedComment(commentID string) (TrackedComment, bool) {
+	var result TrackedComment
+	filter := bson.M{"comment_id": commentID}
+	err := trackedCommentsCollection.FindOne(context.TODO(), filter).Decode(&result)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return TrackedComment{}, false
+	}
+	if err != nil {
+		log.Println("Error querying tracked comment:", err)
+		return TrackedComment{}, false
+	}
+	return result, true
+}
+
+// upsertTrackedComment records the latest known state of a comment.
+func upsertTrackedComment(tc TrackedComment) {
+	filter := bson.M{"comment_id": tc.CommentID}
+	update := bson.M{"$set": tc}
+	opts := options.Update().SetUpsert(true)
+
+	_, err := trackedCommentsCollection.UpdateOne(context.TODO(), filter, update, opts)
+	if err != nil {
+		log.Println("Error upserting tracked comment:", err)
+	}
+}
+
+// reconcileDeletedComments finds every tracked comment under postID that
+// wasn't present in the most recent fetch and marks it deleted, propagating
+// a deletion event to the matches collection for any rule that had matched it.
+func reconcileDeletedComments(postID string, fetchedIDs map[string]bool) {
+	filter := bson.M{"post_id": postID, "deleted_at": nil}
+	cursor, err := trackedCommentsCollection.Find(context.TODO(), filter)
+	if err != nil {
+		log.Println("Error finding tracked comments for post:", err)
+		return
+	}
+	defer cursor.Close(context.TODO())
+
+	var tracked []TrackedComment
+	if err := cursor.All(context.TODO(), &tracked); err != nil {
+		log.Println("Error decoding tracked comments for post:", err)
+		return
+	}
+
+	now := time.Now()
+	for _, tc := range tracked {
+		if fetchedIDs[tc.CommentID] {
+			continue
+		}
+
+		tc.DeletedAt = &now
+		upsertTrackedComment(tc)
+
+		if err := markMatchDeleted(tc.CommentID); err != nil {
+			log.Println("Error marking match deleted for comment:", tc.CommentID, err)
+		}
+	}
+}
+
+// matchAndTrackComment is the single dedupe/match path for a comment,
+// shared by the subreddit-path poll (processComments) and the user-path
+// poll (monitorUser), so a comment surfacing through both only ever fires
+// once: it's new or changed only if its content hash differs from the
+// last-tracked record in trackedCommentsCollection.
+func matchAndTrackComment(comment *reddit.Comment, postID, subreddit string, matcher *Matcher, rateLimitKey string) {
+	bodyHash := hashCommentBody(comment.Body)
+	existing, wasTracked := getTrackedComment(comment.ID)
+	if wasTracked && existing.BodyHash == bodyHash {
+		return
+	}
+
+	data := TemplateData{Author: comment.Author, Permalink: comment.Permalink}
+	matches, err := matcher.Match(subreddit, targetCommentBody, comment.Body, data)
+	if err != nil {
+		log.Println("Error matching comment:", err)
+		return
+	}
+
+	matchedRules := make([]string, 0, len(matches))
+	for _, match := range matches {
+		downstreamRef := matchDownstreamRef(match.RuleName, comment.ID)
+		if wasTracked {
+			fmt.Printf("Rule %q re-matched edited comment: %s\n", match.RuleName, comment.Body)
+		} else {
+			fmt.Printf("Rule %q matched comment: %s\n", match.RuleName, comment.Body)
+		}
+		if err := saveMatch(Match{
+			RuleName:      match.RuleName,
+			ItemType:      "comment",
+			ItemID:        comment.ID,
+			PostID:        postID,
+			Subreddit:     subreddit,
+			Rendered:      match.Rendered,
+			Captures:      match.Captures,
+			MatchedAt:     time.Now(),
+			DownstreamRef: downstreamRef,
+		}); err != nil {
+			log.Println("Error saving comment match:", err)
+			continue
+		}
+		matchedRules = append(matchedRules, match.RuleName)
+		recordMatches(rateLimitKey, 1)
+	}
+
+	upsertTrackedComment(TrackedComment{
+		CommentID:        comment.ID,
+		PostID:           postID,
+		BodyHash:         bodyHash,
+		EditedAt:         comment.Edited.Time,
+		LastMatchedRules: matchedRules,
+	})
+}
+
+// processComments fetches post's comment thread, re-matches any comment that
+// is new or has changed since it was last seen (by content hash, not just
+// Reddit's reported edit time), and reconciles comments that have since
+// disappeared from the thread as deleted.
+func processComments(ctx context.Context, client *reddit.Client, post *reddit.Post, subreddit string, matcher *Matcher) error {
+	comments, err := fetchComments(ctx, client, post)
+	if err != nil {
+		return err
+	}
+
+	fetchedIDs := make(map[string]bool, len(comments))
+	key := targetKey(targetSubreddit, subreddit)
+
+	for _, comment := range comments {
+		fetchedIDs[comment.ID] = true
+		matchAndTrackComment(comment, post.ID, subreddit, matcher, key)
+	}
+
+	reconcileDeletedComments(post.ID, fetchedIDs)
+	return nil
+}
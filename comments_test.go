@@ -0,0 +1,16 @@
+package main
+
+import "testing"
+
+func TestHashCommentBody(t *testing.T) {
+	a := hashCommentBody("hello world")
+	b := hashCommentBody("hello world")
+	c := hashCommentBody("hello world!")
+
+	if a != b {
+		t.Errorf("hashCommentBody() not stable for identical input: %q != %q", a, b)
+	}
+	if a == c {
+		t.Errorf("hashCommentBody() produced the same hash for different bodies: %q", a)
+	}
+}
@@ -0,0 +1,119 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"os/signal"
+	"sync"
+	"syscall"
+
+	"github.com/BurntSushi/toml"
+)
+
+// configPath is the flag-configurable location of the rules config file.
+var configPath = flag.String("config", "config.toml", "path to the rules config file (TOML)")
+
+// RuleConfig is a single entry in the config file describing a regex/template
+// pair to run against incoming posts and comments.
+type RuleConfig struct {
+	Name          string   `toml:"name" json:"name" bson:"name"`
+	Regex         string   `toml:"regex" json:"regex" bson:"regex"`
+	ReplyTemplate string   `toml:"reply_template" json:"reply_template" bson:"reply_template"`
+	Targets       []string `toml:"targets" json:"targets" bson:"targets"`          // any of: "title", "body", "comment"
+	Subreddits    []string `toml:"subreddits" json:"subreddits" bson:"subreddits"` // subreddits this rule applies to
+}
+
+// Config is the top-level shape of the rules config file.
+type Config struct {
+	Rules []RuleConfig `toml:"rules"`
+}
+
+// configState holds the currently loaded config and the compiled matcher
+// derived from it, guarded by a mutex so SIGHUP reloads are safe to read
+// concurrently from the poll loops.
+type configState struct {
+	mu      sync.RWMutex
+	cfg     *Config
+	matcher *Matcher
+}
+
+var liveConfig = &configState{}
+
+// loadConfigFile reads and parses the config file at path, enforcing that it
+// is not group/world readable before trusting its contents.
+func loadConfigFile(path string) (*Config, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, fmt.Errorf("stat config file: %w", err)
+	}
+	if info.Mode().Perm()&0077 != 0 {
+		return nil, fmt.Errorf("config file %s has overly permissive mode %o, expected 0600", path, info.Mode().Perm())
+	}
+
+	var cfg Config
+	if _, err := toml.DecodeFile(path, &cfg); err != nil {
+		return nil, fmt.Errorf("decode config file: %w", err)
+	}
+	return &cfg, nil
+}
+
+// reloadConfig loads configPath from disk, merges in any rules added at
+// runtime via the control API, compiles the combined rule set, and swaps it
+// into liveConfig. Existing monitor goroutines pick up the new rules on
+// their next poll.
+func reloadConfig() error {
+	cfg, err := loadConfigFile(*configPath)
+	if err != nil {
+		return err
+	}
+
+	dynamicRules, err := listDynamicRules()
+	if err != nil {
+		return fmt.Errorf("list dynamic rules: %w", err)
+	}
+	cfg.Rules = append(cfg.Rules, dynamicRules...)
+
+	matcher, err := compileMatcher(cfg)
+	if err != nil {
+		return fmt.Errorf("compile rules: %w", err)
+	}
+
+	liveConfig.mu.Lock()
+	liveConfig.cfg = cfg
+	liveConfig.matcher = matcher
+	liveConfig.mu.Unlock()
+
+	log.Printf("loaded %d rule(s) from %s (%d added at runtime)", len(cfg.Rules), *configPath, len(dynamicRules))
+	return nil
+}
+
+// currentMatcher returns the matcher compiled from the most recently loaded
+// config.
+func currentMatcher() *Matcher {
+	liveConfig.mu.RLock()
+	defer liveConfig.mu.RUnlock()
+	return liveConfig.matcher
+}
+
+// watchConfigReload blocks on SIGHUP in a goroutine and reloads the config
+// file each time it's received, logging any reload error without crashing.
+func watchConfigReload(ctx interface{ Done() <-chan struct{} }) {
+	sighup := make(chan os.Signal, 1)
+	signal.Notify(sighup, syscall.SIGHUP)
+	go func() {
+		for {
+			select {
+			case <-ctx.Done():
+				signal.Stop(sighup)
+				return
+			case <-sighup:
+				log.Println("received SIGHUP, reloading config")
+				if err := reloadConfig(); err != nil {
+					log.Println("error reloading config:", err)
+				}
+			}
+		}
+	}()
+}
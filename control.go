@@ -0,0 +1,166 @@
+package main
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/vartanbeno/go-reddit/v2/reddit"
+)
+
+// controlServer is the HTTP control plane for managing watched
+// subreddits/users/rules at runtime, without a rebuild or restart.
+type controlServer struct {
+	redditClient *reddit.Client
+}
+
+func newControlRouter(cs *controlServer) http.Handler {
+	r := chi.NewRouter()
+
+	r.Post("/subreddits", cs.handleAddSubreddit)
+	r.Delete("/subreddits/{name}", cs.handleRemoveTarget(targetSubreddit))
+	r.Get("/subreddits", cs.handleListTargets(targetSubreddit))
+
+	r.Post("/users", cs.handleAddUser)
+	r.Delete("/users/{name}", cs.handleRemoveTarget(targetUser))
+	r.Get("/users", cs.handleListTargets(targetUser))
+
+	r.Post("/rules", cs.handleAddRule)
+	r.Delete("/rules/{name}", cs.handleRemoveRule)
+	r.Get("/rules", cs.handleListRules)
+
+	return r
+}
+
+func writeJSON(w http.ResponseWriter, status int, body interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(body)
+}
+
+func writeError(w http.ResponseWriter, status int, err error) {
+	writeJSON(w, status, map[string]string{"error": err.Error()})
+}
+
+type addTargetRequest struct {
+	Name string `json:"name"`
+}
+
+// handleAddSubreddit resolves and canonicalizes the subreddit name via
+// Reddit before persisting it, rejecting banned/private/non-existent subs.
+func (cs *controlServer) handleAddSubreddit(w http.ResponseWriter, r *http.Request) {
+	var req addTargetRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	canonicalName, err := resolveSubredditName(r.Context(), cs.redditClient, req.Name)
+	if err != nil {
+		writeError(w, http.StatusUnprocessableEntity, err)
+		return
+	}
+
+	if err := addWatchedTarget(targetSubreddit, canonicalName); err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+
+	writeJSON(w, http.StatusCreated, addTargetRequest{Name: canonicalName})
+}
+
+// handleAddUser adds a redditor to watch. Unlike subreddits, usernames
+// aren't canonicalized against a search endpoint or checked for existence;
+// a username that doesn't exist is still stored, and its monitor keeps
+// getting fetch errors and backing off.
+func (cs *controlServer) handleAddUser(w http.ResponseWriter, r *http.Request) {
+	var req addTargetRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+	if req.Name == "" {
+		writeError(w, http.StatusBadRequest, errMissingName)
+		return
+	}
+
+	if err := addWatchedTarget(targetUser, req.Name); err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+
+	writeJSON(w, http.StatusCreated, req)
+}
+
+func (cs *controlServer) handleRemoveTarget(t targetType) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		name := chi.URLParam(r, "name")
+		if err := removeWatchedTarget(t, name); err != nil {
+			writeError(w, http.StatusInternalServerError, err)
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
+
+type targetStatus struct {
+	Name  string              `json:"name"`
+	Stats TargetStatsSnapshot `json:"stats"`
+}
+
+func (cs *controlServer) handleListTargets(t targetType) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		targets, err := listWatchedTargets(t)
+		if err != nil {
+			writeError(w, http.StatusInternalServerError, err)
+			return
+		}
+
+		statuses := make([]targetStatus, 0, len(targets))
+		for _, target := range targets {
+			stats := registry.statsFor(targetKey(t, target.Name)).snapshot()
+			statuses = append(statuses, targetStatus{Name: target.Name, Stats: stats})
+		}
+
+		writeJSON(w, http.StatusOK, statuses)
+	}
+}
+
+// handleAddRule adds (or replaces) a rule at runtime and triggers a matcher
+// recompile so it's applied on the next poll.
+func (cs *controlServer) handleAddRule(w http.ResponseWriter, r *http.Request) {
+	var rule RuleConfig
+	if err := json.NewDecoder(r.Body).Decode(&rule); err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+	if rule.Name == "" {
+		writeError(w, http.StatusBadRequest, errMissingName)
+		return
+	}
+
+	if err := addDynamicRule(rule); err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+
+	writeJSON(w, http.StatusCreated, rule)
+}
+
+func (cs *controlServer) handleRemoveRule(w http.ResponseWriter, r *http.Request) {
+	name := chi.URLParam(r, "name")
+	if err := removeDynamicRule(name); err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (cs *controlServer) handleListRules(w http.ResponseWriter, r *http.Request) {
+	rules, err := listDynamicRules()
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, rules)
+}
@@ -0,0 +1,53 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"log"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+var processedItemsCollection *mongo.Collection
+
+// ProcessedItem records that a rule has already fired for a given post or
+// comment, so a restart or a re-poll never triggers the same rule twice for
+// the same item.
+type ProcessedItem struct {
+	RuleName    string    `bson:"rule_name"`
+	ItemID      string    `bson:"item_id"`
+	ProcessedAt time.Time `bson:"processed_at"`
+}
+
+// alreadyProcessed reports whether ruleName has already fired for itemID.
+func alreadyProcessed(ruleName, itemID string) bool {
+	filter := bson.M{"rule_name": ruleName, "item_id": itemID}
+	err := processedItemsCollection.FindOne(context.TODO(), filter).Err()
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return false
+	}
+	if err != nil {
+		log.Println("Error querying processed items:", err)
+		return false
+	}
+	return true
+}
+
+// markProcessed records that ruleName has fired for itemID.
+func markProcessed(ruleName, itemID string) {
+	filter := bson.M{"rule_name": ruleName, "item_id": itemID}
+	update := bson.M{"$setOnInsert": ProcessedItem{
+		RuleName:    ruleName,
+		ItemID:      itemID,
+		ProcessedAt: time.Now(),
+	}}
+
+	opts := options.Update().SetUpsert(true)
+	_, err := processedItemsCollection.UpdateOne(context.TODO(), filter, update, opts)
+	if err != nil {
+		log.Println("Error marking item as processed:", err)
+	}
+}
@@ -0,0 +1,101 @@
+package main
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/go-redis/redis/v8"
+)
+
+const (
+	leaseTTL     = 30 * time.Second
+	leaseRefresh = 10 * time.Second
+)
+
+// refreshLeaseScript extends key's TTL only if replicaID still holds it, so a
+// replica can never accidentally refresh a lease another replica has since
+// taken over.
+var refreshLeaseScript = redis.NewScript(`
+if redis.call("GET", KEYS[1]) == ARGV[1] then
+	return redis.call("PEXPIRE", KEYS[1], ARGV[2])
+end
+return 0
+`)
+
+// releaseLeaseScript deletes key only if replicaID still holds it.
+var releaseLeaseScript = redis.NewScript(`
+if redis.call("GET", KEYS[1]) == ARGV[1] then
+	return redis.call("DEL", KEYS[1])
+end
+return 0
+`)
+
+// acquireLease attempts to take an exclusive, TTL'd lease on key, so exactly
+// one replica runs the corresponding monitor goroutine at a time.
+func acquireLease(ctx context.Context, client *redis.Client, key, replicaID string) bool {
+	ok, err := client.SetNX(ctx, key, replicaID, leaseTTL).Result()
+	if err != nil {
+		log.Println("Error acquiring lease", key, ":", err)
+		return false
+	}
+	return ok
+}
+
+// holdLease refreshes key's TTL on an interval for as long as ctx is alive.
+// If the lease is ever lost to another replica, it calls cancel so the
+// owning monitor goroutine exits cleanly via its existing ctx.Done() path.
+func holdLease(ctx context.Context, cancel context.CancelFunc, client *redis.Client, key, replicaID string) {
+	ticker := time.NewTicker(leaseRefresh)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			refreshed, err := refreshLeaseScript.Run(ctx, client, []string{key}, replicaID, leaseTTL.Milliseconds()).Int()
+			if err != nil {
+				log.Println("Error refreshing lease", key, ":", err)
+				continue
+			}
+			if refreshed == 0 {
+				log.Println("Lost lease for", key, "- stopping monitor")
+				cancel()
+				return
+			}
+		}
+	}
+}
+
+// releaseLease drops key immediately instead of waiting out its TTL, so
+// another replica can pick up the target right away on clean shutdown.
+func releaseLease(client *redis.Client, key, replicaID string) {
+	if err := releaseLeaseScript.Run(context.Background(), client, []string{key}, replicaID).Err(); err != nil {
+		log.Println("Error releasing lease", key, ":", err)
+	}
+}
+
+// runLeased starts fn in a goroutine only if this replica can take the lease
+// for key, keeping the lease refreshed for as long as fn runs and releasing
+// it on exit. Call this once per subreddit/user target so exactly one
+// replica monitors it at a time; on startup, each replica picks up any
+// target whose lease nobody currently holds. Reports whether it started.
+func runLeased(ctx context.Context, client *redis.Client, registry *targetRegistry, key, replicaID string, fn func(context.Context)) bool {
+	if !acquireLease(ctx, client, key, replicaID) {
+		return false
+	}
+
+	leaseCtx, cancel := context.WithCancel(ctx)
+	registry.start(key, cancel)
+
+	go holdLease(leaseCtx, cancel, client, key, replicaID)
+
+	go func() {
+		fn(leaseCtx)
+		registry.stop(key)
+		releaseLease(client, key, replicaID)
+	}()
+
+	return true
+}
@@ -0,0 +1,128 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"text/template"
+
+	"github.com/Masterminds/sprig/v3"
+	"go.elara.ws/pcre"
+)
+
+// matchTarget enumerates the parts of a post/comment a rule can be applied to.
+type matchTarget string
+
+const (
+	targetPostTitle   matchTarget = "title"
+	targetPostBody    matchTarget = "body"
+	targetCommentBody matchTarget = "comment"
+)
+
+// compiledRule is a RuleConfig with its regex and template pre-compiled.
+type compiledRule struct {
+	name       string
+	regex      *pcre.Regexp
+	tmpl       *template.Template
+	targets    map[matchTarget]bool
+	subreddits map[string]bool
+}
+
+// Matcher runs every compiled rule against incoming text and renders the
+// matched rule's reply/summary template.
+type Matcher struct {
+	rules []*compiledRule
+}
+
+// TemplateData is the set of fields available to a rule's reply_template.
+type TemplateData struct {
+	Author    string
+	Permalink string
+	Subreddit string
+	Match     string
+	Captures  []string
+}
+
+// MatchResult is a single rule firing against a single piece of text.
+type MatchResult struct {
+	RuleName string
+	Rendered string
+	Captures []string
+}
+
+// compileMatcher compiles every rule in cfg into a Matcher, failing fast if
+// any regex or template is invalid.
+func compileMatcher(cfg *Config) (*Matcher, error) {
+	m := &Matcher{rules: make([]*compiledRule, 0, len(cfg.Rules))}
+
+	for _, rc := range cfg.Rules {
+		re, err := pcre.Compile(rc.Regex)
+		if err != nil {
+			return nil, fmt.Errorf("rule %q: compile regex: %w", rc.Name, err)
+		}
+
+		tmpl, err := template.New(rc.Name).Funcs(sprig.TxtFuncMap()).Parse(rc.ReplyTemplate)
+		if err != nil {
+			return nil, fmt.Errorf("rule %q: parse template: %w", rc.Name, err)
+		}
+
+		targets := make(map[matchTarget]bool, len(rc.Targets))
+		for _, t := range rc.Targets {
+			targets[matchTarget(t)] = true
+		}
+
+		subreddits := make(map[string]bool, len(rc.Subreddits))
+		for _, s := range rc.Subreddits {
+			subreddits[s] = true
+		}
+
+		m.rules = append(m.rules, &compiledRule{
+			name:       rc.Name,
+			regex:      re,
+			tmpl:       tmpl,
+			targets:    targets,
+			subreddits: subreddits,
+		})
+	}
+
+	return m, nil
+}
+
+// Match runs every rule applicable to target/subreddit against text and
+// returns one MatchResult per rule that matched.
+func (m *Matcher) Match(subreddit string, target matchTarget, text string, data TemplateData) ([]MatchResult, error) {
+	if m == nil || text == "" {
+		return nil, nil
+	}
+
+	var results []MatchResult
+	for _, rule := range m.rules {
+		if len(rule.targets) > 0 && !rule.targets[target] {
+			continue
+		}
+		if len(rule.subreddits) > 0 && !rule.subreddits[subreddit] {
+			continue
+		}
+
+		groups := rule.regex.FindStringSubmatch(text)
+		if groups == nil {
+			continue
+		}
+
+		data.Subreddit = subreddit
+		data.Match = groups[0]
+		data.Captures = groups[1:]
+
+		var buf bytes.Buffer
+		if err := rule.tmpl.Execute(&buf, data); err != nil {
+			return results, fmt.Errorf("rule %q: render template: %w", rule.name, err)
+		}
+
+		results = append(results, MatchResult{
+			RuleName: rule.name,
+			Rendered: buf.String(),
+			Captures: data.Captures,
+		})
+	}
+
+	return results, nil
+}
@@ -0,0 +1,109 @@
+package main
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestCompileMatcher(t *testing.T) {
+	t.Run("valid rule compiles", func(t *testing.T) {
+		cfg := &Config{Rules: []RuleConfig{
+			{Name: "greet", Regex: `hello (\w+)`, ReplyTemplate: "hi {{index .Captures 0}}"},
+		}}
+		if _, err := compileMatcher(cfg); err != nil {
+			t.Fatalf("compileMatcher() error = %v, want nil", err)
+		}
+	})
+
+	t.Run("invalid regex fails", func(t *testing.T) {
+		cfg := &Config{Rules: []RuleConfig{
+			{Name: "bad", Regex: `(unclosed`, ReplyTemplate: "{{.Match}}"},
+		}}
+		if _, err := compileMatcher(cfg); err == nil {
+			t.Fatal("compileMatcher() error = nil, want error for invalid regex")
+		}
+	})
+
+	t.Run("invalid template fails", func(t *testing.T) {
+		cfg := &Config{Rules: []RuleConfig{
+			{Name: "bad", Regex: `.*`, ReplyTemplate: "{{.Unclosed"},
+		}}
+		if _, err := compileMatcher(cfg); err == nil {
+			t.Fatal("compileMatcher() error = nil, want error for invalid template")
+		}
+	})
+}
+
+func TestMatcherMatch(t *testing.T) {
+	cfg := &Config{Rules: []RuleConfig{
+		{
+			Name:          "golang-mention",
+			Regex:         `(?i)golang`,
+			ReplyTemplate: "{{.Author}} mentioned golang in r/{{.Subreddit}}",
+			Targets:       []string{"title", "comment"},
+			Subreddits:    []string{"programming"},
+		},
+	}}
+	matcher, err := compileMatcher(cfg)
+	if err != nil {
+		t.Fatalf("compileMatcher() error = %v", err)
+	}
+
+	data := TemplateData{Author: "alice", Permalink: "/r/programming/1"}
+
+	t.Run("matches applicable target and subreddit", func(t *testing.T) {
+		results, err := matcher.Match("programming", targetPostTitle, "Why I love Golang", data)
+		if err != nil {
+			t.Fatalf("Match() error = %v", err)
+		}
+		if len(results) != 1 {
+			t.Fatalf("Match() returned %d results, want 1", len(results))
+		}
+		if results[0].RuleName != "golang-mention" {
+			t.Errorf("RuleName = %q, want %q", results[0].RuleName, "golang-mention")
+		}
+		if !strings.Contains(results[0].Rendered, "alice") {
+			t.Errorf("Rendered = %q, want it to contain author", results[0].Rendered)
+		}
+	})
+
+	t.Run("skips target the rule doesn't apply to", func(t *testing.T) {
+		results, err := matcher.Match("programming", targetPostBody, "Why I love Golang", data)
+		if err != nil {
+			t.Fatalf("Match() error = %v", err)
+		}
+		if len(results) != 0 {
+			t.Errorf("Match() returned %d results, want 0", len(results))
+		}
+	})
+
+	t.Run("skips subreddit the rule doesn't apply to", func(t *testing.T) {
+		results, err := matcher.Match("golang", targetPostTitle, "Why I love Golang", data)
+		if err != nil {
+			t.Fatalf("Match() error = %v", err)
+		}
+		if len(results) != 0 {
+			t.Errorf("Match() returned %d results, want 0", len(results))
+		}
+	})
+
+	t.Run("no match returns nothing", func(t *testing.T) {
+		results, err := matcher.Match("programming", targetPostTitle, "Why I love Rust", data)
+		if err != nil {
+			t.Fatalf("Match() error = %v", err)
+		}
+		if len(results) != 0 {
+			t.Errorf("Match() returned %d results, want 0", len(results))
+		}
+	})
+
+	t.Run("empty text returns nothing", func(t *testing.T) {
+		results, err := matcher.Match("programming", targetPostTitle, "", data)
+		if err != nil {
+			t.Fatalf("Match() error = %v", err)
+		}
+		if len(results) != 0 {
+			t.Errorf("Match() returned %d results, want 0", len(results))
+		}
+	})
+}
@@ -0,0 +1,87 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/go-redis/redis/v8"
+)
+
+// RateLimiter enforces the shared Reddit API request quota. Wait blocks
+// until the caller is allowed to make one request, or returns ctx.Err() if
+// ctx is cancelled first.
+type RateLimiter interface {
+	Wait(ctx context.Context) error
+}
+
+// localRateLimiter is a process-local token bucket. It only enforces the
+// quota correctly when a single replica of this binary is running; run two
+// and they'll independently burn through Reddit's quota.
+type localRateLimiter struct {
+	tick <-chan time.Time
+}
+
+func newLocalRateLimiter(requestsPerMinute int) *localRateLimiter {
+	return &localRateLimiter{tick: time.Tick(time.Minute / time.Duration(requestsPerMinute))}
+}
+
+func (l *localRateLimiter) Wait(ctx context.Context) error {
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-l.tick:
+		return nil
+	}
+}
+
+// tokenBucketScript atomically increments the per-window request counter,
+// arming its expiry only on the window's first increment, and reports
+// whether the caller is still within budget for the current window.
+var tokenBucketScript = redis.NewScript(`
+local count = redis.call("INCR", KEYS[1])
+if count == 1 then
+	redis.call("PEXPIRE", KEYS[1], ARGV[1])
+end
+if count > tonumber(ARGV[2]) then
+	return 0
+end
+return 1
+`)
+
+// redisRateLimiter is a distributed token bucket shared across replicas via
+// Redis, so N replicas cooperatively stay under requestsPerMinute instead of
+// each enforcing it independently.
+type redisRateLimiter struct {
+	client            *redis.Client
+	key               string
+	requestsPerMinute int
+}
+
+// newRedisRateLimiter builds a limiter keyed by clientID, so distinct Reddit
+// OAuth apps don't share a quota by accident.
+func newRedisRateLimiter(client *redis.Client, clientID string, requestsPerMinute int) *redisRateLimiter {
+	return &redisRateLimiter{
+		client:            client,
+		key:               fmt.Sprintf("reddit:ratelimit:%s", clientID),
+		requestsPerMinute: requestsPerMinute,
+	}
+}
+
+func (r *redisRateLimiter) Wait(ctx context.Context) error {
+	for {
+		allowed, err := tokenBucketScript.Run(ctx, r.client, []string{r.key}, time.Minute.Milliseconds(), r.requestsPerMinute).Int()
+		if err != nil {
+			return fmt.Errorf("rate limit script: %w", err)
+		}
+		if allowed == 1 {
+			return nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(time.Second):
+		}
+	}
+}
@@ -0,0 +1,62 @@
+package main
+
+import (
+	"context"
+	"fmt"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+var rulesCollection *mongo.Collection
+
+// listDynamicRules returns every rule added at runtime through the control
+// API, on top of whatever is in the static TOML config file.
+func listDynamicRules() ([]RuleConfig, error) {
+	if rulesCollection == nil {
+		return nil, nil
+	}
+
+	cursor, err := rulesCollection.Find(context.TODO(), bson.M{})
+	if err != nil {
+		return nil, fmt.Errorf("list rules: %w", err)
+	}
+	defer cursor.Close(context.TODO())
+
+	var rules []RuleConfig
+	if err := cursor.All(context.TODO(), &rules); err != nil {
+		return nil, fmt.Errorf("decode rules: %w", err)
+	}
+	return rules, nil
+}
+
+// addDynamicRule validates that rule's regex and template compile, then
+// upserts it by name into the rules collection and triggers a matcher
+// recompile so it takes effect immediately. Validating before the write
+// keeps a bad rule from ever reaching Mongo, where it would fail every
+// subsequent reloadConfig (including the one at process startup).
+func addDynamicRule(rule RuleConfig) error {
+	if _, err := compileMatcher(&Config{Rules: []RuleConfig{rule}}); err != nil {
+		return fmt.Errorf("invalid rule: %w", err)
+	}
+
+	filter := bson.M{"name": rule.Name}
+	update := bson.M{"$set": rule}
+	opts := options.Update().SetUpsert(true)
+	_, err := rulesCollection.UpdateOne(context.TODO(), filter, update, opts)
+	if err != nil {
+		return fmt.Errorf("add rule: %w", err)
+	}
+	return reloadConfig()
+}
+
+// removeDynamicRule deletes a runtime rule by name and recompiles the
+// matcher. It has no effect on rules defined in the static TOML config.
+func removeDynamicRule(name string) error {
+	_, err := rulesCollection.DeleteOne(context.TODO(), bson.M{"name": name})
+	if err != nil {
+		return fmt.Errorf("remove rule: %w", err)
+	}
+	return reloadConfig()
+}
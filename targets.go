@@ -0,0 +1,291 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/vartanbeno/go-reddit/v2/reddit"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+var watchedTargetsCollection *mongo.Collection
+
+// registry tracks every currently-running monitor goroutine and its live
+// stats, read by the control API's GET endpoints.
+var registry = newTargetRegistry()
+
+// targetType distinguishes a watched subreddit from a watched redditor.
+type targetType string
+
+const (
+	targetSubreddit targetType = "subreddit"
+	targetUser      targetType = "user"
+)
+
+// WatchedTarget is a subreddit or user the control API has been told to
+// monitor. It's the desired state the reconciliation loop in main diffs
+// against the monitor goroutines actually running.
+type WatchedTarget struct {
+	Type      targetType `bson:"type"`
+	Name      string     `bson:"name"`
+	CreatedAt time.Time  `bson:"created_at"`
+}
+
+// targetKey is the targetRegistry cancels/stats map key for a target.
+func targetKey(t targetType, name string) string {
+	return fmt.Sprintf("%s:%s", t, name)
+}
+
+// listWatchedTargets returns every watched target of the given type.
+func listWatchedTargets(t targetType) ([]WatchedTarget, error) {
+	cursor, err := watchedTargetsCollection.Find(context.TODO(), bson.M{"type": t})
+	if err != nil {
+		return nil, fmt.Errorf("list watched targets: %w", err)
+	}
+	defer cursor.Close(context.TODO())
+
+	var targets []WatchedTarget
+	if err := cursor.All(context.TODO(), &targets); err != nil {
+		return nil, fmt.Errorf("decode watched targets: %w", err)
+	}
+	return targets, nil
+}
+
+// addWatchedTarget upserts a watched target so the reconciliation loop
+// spawns a monitor goroutine for it.
+func addWatchedTarget(t targetType, name string) error {
+	filter := bson.M{"type": t, "name": name}
+	update := bson.M{"$setOnInsert": WatchedTarget{Type: t, Name: name, CreatedAt: time.Now()}}
+	opts := options.Update().SetUpsert(true)
+
+	_, err := watchedTargetsCollection.UpdateOne(context.TODO(), filter, update, opts)
+	if err != nil {
+		return fmt.Errorf("add watched target: %w", err)
+	}
+	return nil
+}
+
+// removeWatchedTarget deletes a watched target so the reconciliation loop
+// cancels its monitor goroutine.
+func removeWatchedTarget(t targetType, name string) error {
+	filter := bson.M{"type": t, "name": name}
+	_, err := watchedTargetsCollection.DeleteOne(context.TODO(), filter)
+	if err != nil {
+		return fmt.Errorf("remove watched target: %w", err)
+	}
+	return nil
+}
+
+// TargetStats is the live, mutex-guarded operability counters for a running
+// monitor goroutine. It's never copied by value - snapshot() below produces
+// the plain TargetStatsSnapshot that's safe to pass around and serialize.
+type TargetStats struct {
+	mu          sync.Mutex
+	PostsSeen   int
+	MatchesSeen int
+	LastPoll    time.Time
+	Backoff     time.Duration
+}
+
+// TargetStatsSnapshot is a point-in-time, lock-free copy of TargetStats,
+// surfaced by the control API's GET endpoints.
+type TargetStatsSnapshot struct {
+	PostsSeen   int           `json:"posts_seen"`
+	MatchesSeen int           `json:"matches_seen"`
+	LastPoll    time.Time     `json:"last_poll"`
+	Backoff     time.Duration `json:"current_backoff"`
+}
+
+func (s *TargetStats) snapshot() TargetStatsSnapshot {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return TargetStatsSnapshot{
+		PostsSeen:   s.PostsSeen,
+		MatchesSeen: s.MatchesSeen,
+		LastPoll:    s.LastPoll,
+		Backoff:     s.Backoff,
+	}
+}
+
+// targetRegistry tracks every currently-running monitor goroutine (keyed by
+// targetKey) alongside its cancel func and live stats, so the reconciliation
+// loop and the control API's GET endpoints can both inspect/mutate it.
+type targetRegistry struct {
+	mu      sync.Mutex
+	cancels map[string]context.CancelFunc
+	stats   map[string]*TargetStats
+}
+
+func newTargetRegistry() *targetRegistry {
+	return &targetRegistry{
+		cancels: make(map[string]context.CancelFunc),
+		stats:   make(map[string]*TargetStats),
+	}
+}
+
+// statsFor returns (creating if necessary) the TargetStats for key, so
+// monitor goroutines can record activity without knowing about the registry's
+// locking.
+func (r *targetRegistry) statsFor(key string) *TargetStats {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	s, ok := r.stats[key]
+	if !ok {
+		s = &TargetStats{}
+		r.stats[key] = s
+	}
+	return s
+}
+
+func (r *targetRegistry) isRunning(key string) bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	_, ok := r.cancels[key]
+	return ok
+}
+
+func (r *targetRegistry) running() map[string]bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	out := make(map[string]bool, len(r.cancels))
+	for key := range r.cancels {
+		out[key] = true
+	}
+	return out
+}
+
+func (r *targetRegistry) start(key string, cancel context.CancelFunc) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.cancels[key] = cancel
+}
+
+func (r *targetRegistry) stop(key string) {
+	r.mu.Lock()
+	cancel, ok := r.cancels[key]
+	delete(r.cancels, key)
+	delete(r.stats, key)
+	r.mu.Unlock()
+
+	if ok {
+		cancel()
+	}
+}
+
+// reconcileTargets diffs the desired watched_targets collection against the
+// monitor goroutines actually running and spawns/cancels them to match,
+// polling on an interval since watched_targets can change at any time via
+// the control API. spawnSubreddit/spawnUser are expected to take the
+// per-target lease themselves (see runLeased) and register with registry;
+// a target this replica couldn't lease is simply retried next tick.
+func reconcileTargets(ctx context.Context, registry *targetRegistry, spawnSubreddit func(context.Context, string), spawnUser func(context.Context, string)) {
+	ticker := time.NewTicker(10 * time.Second)
+	defer ticker.Stop()
+
+	reconcileOnce := func() {
+		desired := make(map[string]bool)
+
+		for _, t := range mustList(targetSubreddit) {
+			desired[targetKey(targetSubreddit, t.Name)] = true
+			if !registry.isRunning(targetKey(targetSubreddit, t.Name)) {
+				spawnSubreddit(ctx, t.Name)
+			}
+		}
+		for _, t := range mustList(targetUser) {
+			desired[targetKey(targetUser, t.Name)] = true
+			if !registry.isRunning(targetKey(targetUser, t.Name)) {
+				spawnUser(ctx, t.Name)
+			}
+		}
+
+		// Cancel anything running but no longer desired.
+		for key := range registry.running() {
+			if !desired[key] {
+				registry.stop(key)
+			}
+		}
+	}
+
+	reconcileOnce()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			reconcileOnce()
+		}
+	}
+}
+
+// recordPoll updates a target's last-poll time and running posts-seen count.
+func recordPoll(key string, postsSeen int) {
+	s := registry.statsFor(key)
+	s.mu.Lock()
+	s.LastPoll = time.Now()
+	s.PostsSeen += postsSeen
+	s.mu.Unlock()
+}
+
+// recordMatches adds n to a target's running matches-seen count.
+func recordMatches(key string, n int) {
+	if n == 0 {
+		return
+	}
+	s := registry.statsFor(key)
+	s.mu.Lock()
+	s.MatchesSeen += n
+	s.mu.Unlock()
+}
+
+// recordBackoff records the backoff currently being applied to a target
+// after a fetch error (zero once it's recovered).
+func recordBackoff(key string, backoff time.Duration) {
+	s := registry.statsFor(key)
+	s.mu.Lock()
+	s.Backoff = backoff
+	s.mu.Unlock()
+}
+
+// mustList lists watched targets of type t, logging (rather than failing)
+// on error so a transient Mongo hiccup doesn't tear down every monitor.
+func mustList(t targetType) []WatchedTarget {
+	targets, err := listWatchedTargets(t)
+	if err != nil {
+		log.Println("Error listing watched targets:", err)
+		return nil
+	}
+	return targets
+}
+
+var (
+	errSubredditUnavailable = errors.New("subreddit is banned, private, or does not exist")
+	errMissingName          = errors.New("name is required")
+)
+
+// resolveSubredditName looks up name via Reddit's subreddit "about" endpoint
+// and returns its canonical (correctly-cased) name, rejecting subreddits
+// that are banned, private, or don't exist.
+func resolveSubredditName(ctx context.Context, client *reddit.Client, name string) (string, error) {
+	sub, _, err := client.Subreddit.Get(ctx, name)
+	if err != nil {
+		return "", fmt.Errorf("%w: %v", errSubredditUnavailable, err)
+	}
+	if sub == nil {
+		return "", errSubredditUnavailable
+	}
+
+	switch sub.Type {
+	case "public", "":
+		// ok
+	default:
+		return "", fmt.Errorf("%w: subreddit_type=%s", errSubredditUnavailable, sub.Type)
+	}
+
+	return sub.Name, nil
+}
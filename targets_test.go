@@ -0,0 +1,20 @@
+package main
+
+import "testing"
+
+func TestTargetKey(t *testing.T) {
+	tests := []struct {
+		typ  targetType
+		name string
+		want string
+	}{
+		{targetSubreddit, "golang", "subreddit:golang"},
+		{targetUser, "someuser", "user:someuser"},
+	}
+
+	for _, tt := range tests {
+		if got := targetKey(tt.typ, tt.name); got != tt.want {
+			t.Errorf("targetKey(%q, %q) = %q, want %q", tt.typ, tt.name, got, tt.want)
+		}
+	}
+}
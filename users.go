@@ -0,0 +1,158 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/vartanbeno/go-reddit/v2/reddit"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+var trackedUsersCollection *mongo.Collection
+
+// TrackedUser records a redditor being watched and the cursor of the last
+// post/comment we've already processed for them, so a restart doesn't
+// reprocess their whole history.
+type TrackedUser struct {
+	Username          string `bson:"username"`
+	LastSeenPostID    string `bson:"last_seen_post_id"`
+	LastSeenCommentID string `bson:"last_seen_comment_id"`
+}
+
+// getUserCursor loads the stored cursor for username, returning a zero-value
+// TrackedUser if the user hasn't been seen before.
+func getUserCursor(username string) TrackedUser {
+	var result TrackedUser
+	filter := bson.M{"username": username}
+	err := trackedUsersCollection.FindOne(context.TODO(), filter).Decode(&result)
+	if err != nil && !errors.Is(err, mongo.ErrNoDocuments) {
+		log.Println("Error querying tracked user:", err)
+	}
+	result.Username = username
+	return result
+}
+
+// updateUserCursor advances the stored last-seen post/comment IDs for username.
+func updateUserCursor(username, lastSeenPostID, lastSeenCommentID string) {
+	filter := bson.M{"username": username}
+	update := bson.M{
+		"$set": TrackedUser{
+			Username:          username,
+			LastSeenPostID:    lastSeenPostID,
+			LastSeenCommentID: lastSeenCommentID,
+		},
+	}
+
+	opts := options.Update().SetUpsert(true)
+	_, err := trackedUsersCollection.UpdateOne(context.TODO(), filter, update, opts)
+	if err != nil {
+		log.Println("Error updating tracked user cursor:", err)
+	}
+}
+
+// monitorUser watches a specific redditor's submissions and comments,
+// applying the same regex/template matching and dedupe pipeline as
+// monitorSubreddit.
+func monitorUser(ctx context.Context, client *reddit.Client, username string) {
+	cursor := getUserCursor(username)
+	key := targetKey(targetUser, username)
+	backoff := time.Duration(0)
+
+	for {
+		if err := rateLimiter.Wait(ctx); err != nil {
+			fmt.Println("Stopping monitoring for user:", username)
+			return
+		}
+
+		posts, _, err := client.User.PostsOf(ctx, username, &reddit.ListUserOverviewOptions{
+			ListOptions: reddit.ListOptions{Limit: 100},
+		})
+		if err != nil {
+			log.Println("Error fetching user posts:", err)
+			backoff = nextBackoff(backoff)
+			recordBackoff(key, backoff)
+			time.Sleep(backoff)
+			continue
+		}
+		backoff = 0
+		recordBackoff(key, 0)
+		recordPoll(key, len(posts))
+
+		matcher := currentMatcher()
+		newestPostID := cursor.LastSeenPostID
+
+		for _, post := range posts {
+			if post.ID == cursor.LastSeenPostID {
+				break // caught up to where we left off
+			}
+			if newestPostID == cursor.LastSeenPostID {
+				newestPostID = post.ID
+			}
+
+			data := TemplateData{Author: post.Author, Permalink: post.Permalink}
+			titleMatches, err := matcher.Match(post.SubredditName, targetPostTitle, post.Title, data)
+			if err != nil {
+				log.Println("Error matching user post title:", err)
+			}
+			bodyMatches, err := matcher.Match(post.SubredditName, targetPostBody, post.Body, data)
+			if err != nil {
+				log.Println("Error matching user post body:", err)
+			}
+
+			for _, match := range append(titleMatches, bodyMatches...) {
+				if alreadyProcessed(match.RuleName, post.ID) {
+					continue
+				}
+				fmt.Printf("Rule %q matched post by u/%s: %s\n", match.RuleName, username, post.Title)
+				saveToDatabase(post.SubredditName, post, match)
+				markProcessed(match.RuleName, post.ID)
+				recordMatches(key, 1)
+			}
+		}
+
+		if err := rateLimiter.Wait(ctx); err != nil { // separate call, separate rate-limit slot
+			fmt.Println("Stopping monitoring for user:", username)
+			return
+		}
+		comments, _, err := client.User.CommentsOf(ctx, username, &reddit.ListUserOverviewOptions{
+			ListOptions: reddit.ListOptions{Limit: 100},
+		})
+		if err != nil {
+			log.Println("Error fetching user comments:", err)
+			backoff = nextBackoff(backoff)
+			recordBackoff(key, backoff)
+			time.Sleep(backoff)
+			continue
+		}
+		backoff = 0
+		recordBackoff(key, 0)
+
+		newestCommentID := cursor.LastSeenCommentID
+
+		for _, comment := range comments {
+			if comment.ID == cursor.LastSeenCommentID {
+				break
+			}
+			if newestCommentID == cursor.LastSeenCommentID {
+				newestCommentID = comment.ID
+			}
+
+			// Shares trackedCommentsCollection dedupe with the subreddit-path
+			// poll (processComments), so a comment surfacing through both a
+			// subreddit and a tracked-user poll only ever fires once.
+			matchAndTrackComment(comment, strings.TrimPrefix(comment.PostID, "t3_"), comment.SubredditName, matcher, key)
+		}
+
+		cursor.LastSeenPostID = newestPostID
+		cursor.LastSeenCommentID = newestCommentID
+		updateUserCursor(username, newestPostID, newestCommentID)
+
+		time.Sleep(pollInterval)
+	}
+}